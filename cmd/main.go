@@ -1,23 +1,130 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"flag"
 	"log"
 	"net/http"
+	"os/signal"
 	"receipt-processor/internal/receipt"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
+const (
+	readTimeout       = 5 * time.Second
+	readHeaderTimeout = 5 * time.Second
+	writeTimeout      = 10 * time.Second
+	idleTimeout       = 60 * time.Second
+	maxHeaderBytes    = 1 << 20 // 1 MB
+	shutdownTimeout   = 10 * time.Second
+
+	eventBufferSize    = 1000
+	eventBatchSize     = 20
+	eventFlushInterval = 5 * time.Second
+)
+
 func main() {
+	storePath := flag.String("store-file", "", "path to a JSON-lines receipt log; if empty, receipts are kept in memory only")
+	configPath := flag.String("config", "", "path to a TOML scoring rules config; if empty, the default rules are used")
+	addr := flag.String("addr", ":8080", "address to listen on")
+	eventsStdout := flag.Bool("events-stdout", false, "emit receipt events as JSON lines to stdout")
+	eventsWebhookURL := flag.String("events-webhook-url", "", "URL to POST batched receipt events to; if empty, webhook emission is disabled")
+	flag.Parse()
+
+	store, err := newStore(*storePath)
+	if err != nil {
+		log.Fatalf("failed to initialize receipt store: %v", err)
+	}
+
+	rules, err := newRuleSet(*configPath)
+	if err != nil {
+		log.Fatalf("failed to load rules config: %v", err)
+	}
+
+	events := newPublisher(*eventsStdout, *eventsWebhookURL)
+	defer events.Close()
+
+	h := receipt.NewHandler(store, rules, events)
+
 	// Initialize the router
 	r := mux.NewRouter()
 
 	// Define the routes
-	r.HandleFunc("/receipts/process", receipt.ProcessReceipt).Methods("POST")
-	r.HandleFunc("/receipts/{id}/points", receipt.GetPoints).Methods("GET")
+	r.HandleFunc("/receipts/process", h.ProcessReceipt).Methods("POST")
+	r.HandleFunc("/receipts/{id}/points", h.GetPoints).Methods("GET")
+	r.HandleFunc("/rules", h.Rules).Methods("GET")
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           withRequestID(withAccessLog(withRecovery(r))),
+		ReadTimeout:       readTimeout,
+		ReadHeaderTimeout: readHeaderTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+		MaxHeaderBytes:    maxHeaderBytes,
+	}
+
+	run(srv)
+}
+
+// run starts srv and blocks until SIGINT/SIGTERM triggers a graceful
+// shutdown, draining in-flight requests within shutdownTimeout.
+func run(srv *http.Server) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Printf("server started at %s", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("server error: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Println("shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Fatalf("graceful shutdown failed: %v", err)
+	}
+}
+
+// newStore picks the receipt storage backend: a file-backed store that
+// survives restarts when path is set, or a plain in-memory store otherwise.
+func newStore(path string) (receipt.Store, error) {
+	if path == "" {
+		return receipt.NewMemoryStore(), nil
+	}
+	return receipt.NewFileStore(path)
+}
+
+// newRuleSet loads the scoring rules from path, or falls back to the
+// default rules (matching the service's original hard-coded behavior) if
+// path is empty.
+func newRuleSet(path string) (receipt.RuleSet, error) {
+	if path == "" {
+		return receipt.DefaultRuleSet(), nil
+	}
+	return receipt.LoadRuleSet(path)
+}
 
-	// Start the server
-	fmt.Println("Server started at :8080")
-	log.Fatal(http.ListenAndServe(":8080", r))
+// newPublisher wires up the configured event sinks.
+func newPublisher(stdout bool, webhookURL string) *receipt.Publisher {
+	var sinks []receipt.EventSink
+	if stdout {
+		sinks = append(sinks, receipt.StdoutSink{})
+	}
+	if webhookURL != "" {
+		sinks = append(sinks, receipt.NewWebhookSink(webhookURL, eventBatchSize, eventFlushInterval))
+	}
+	if len(sinks) == 0 {
+		sinks = append(sinks, receipt.NoopSink{})
+	}
+	return receipt.NewPublisher(eventBufferSize, sinks...)
 }
@@ -0,0 +1,229 @@
+package receipt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of activity an Event describes.
+type EventType string
+
+const (
+	EventReceiptProcessed EventType = "receipt.processed"
+	EventPointsCalculated EventType = "receipt.points_calculated"
+	EventReceiptFetched   EventType = "receipt.fetched"
+)
+
+// Event describes one piece of receipt activity for downstream consumers.
+type Event struct {
+	Type       EventType              `json:"type"`
+	Timestamp  time.Time              `json:"timestamp"`
+	ReceiptID  string                 `json:"receiptId"`
+	Retailer   string                 `json:"retailer"`
+	ItemCount  int                    `json:"itemCount"`
+	TotalCents int64                  `json:"totalCents"`
+	Points     int64                  `json:"points,omitempty"`
+	Params     map[string]interface{} `json:"params,omitempty"`
+}
+
+// EventSink receives events emitted by the receipt package.
+type EventSink interface {
+	Emit(ctx context.Context, event Event) error
+}
+
+// NoopSink discards every event. It's the default sink for tests and for
+// deployments that don't care about analytics.
+type NoopSink struct{}
+
+func (NoopSink) Emit(ctx context.Context, event Event) error { return nil }
+
+// StdoutSink writes each event as a JSON line to stdout.
+type StdoutSink struct{}
+
+func (StdoutSink) Emit(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	_, err = fmt.Println(string(data))
+	return err
+}
+
+// Publisher fans events out to a set of sinks asynchronously, so
+// ProcessReceipt/GetPoints never block on a slow sink.
+type Publisher struct {
+	sinks  []EventSink
+	events chan Event
+	wg     sync.WaitGroup
+}
+
+// NewPublisher starts a background worker that delivers events to sinks.
+// bufferSize bounds how many events may queue before Publish starts
+// dropping them rather than blocking the caller.
+func NewPublisher(bufferSize int, sinks ...EventSink) *Publisher {
+	p := &Publisher{
+		sinks:  sinks,
+		events: make(chan Event, bufferSize),
+	}
+	p.wg.Add(1)
+	go p.run()
+	return p
+}
+
+// Publish enqueues event for delivery. If the buffer is full the event is
+// dropped and logged rather than blocking the caller.
+func (p *Publisher) Publish(event Event) {
+	select {
+	case p.events <- event:
+	default:
+		log.Printf("event publisher: buffer full, dropping %s event for receipt %s", event.Type, event.ReceiptID)
+	}
+}
+
+// Close stops accepting new events, waits for queued events to drain, and
+// closes any sink that needs to flush buffered state (e.g. WebhookSink)
+// before shutdown completes.
+func (p *Publisher) Close() {
+	close(p.events)
+	p.wg.Wait()
+
+	for _, sink := range p.sinks {
+		if c, ok := sink.(interface{ Close() }); ok {
+			c.Close()
+		}
+	}
+}
+
+func (p *Publisher) run() {
+	defer p.wg.Done()
+	for event := range p.events {
+		for _, sink := range p.sinks {
+			if err := sink.Emit(context.Background(), event); err != nil {
+				log.Printf("event publisher: sink failed to emit %s event for receipt %s: %v", event.Type, event.ReceiptID, err)
+			}
+		}
+	}
+}
+
+// WebhookSink batches events and POSTs them as a JSON array to a URL. A
+// batch is flushed once it reaches batchSize events or flushInterval has
+// elapsed, whichever comes first. A failed POST is retried with
+// exponential backoff before the batch is dropped.
+type WebhookSink struct {
+	url        string
+	client     *http.Client
+	batchSize  int
+	maxRetries int
+
+	mu      sync.Mutex
+	pending []Event
+	flush   chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewWebhookSink starts a batching worker that flushes to url every
+// batchSize events or flushInterval, whichever comes first.
+func NewWebhookSink(url string, batchSize int, flushInterval time.Duration) *WebhookSink {
+	s := &WebhookSink{
+		url:        url,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		batchSize:  batchSize,
+		maxRetries: 3,
+		flush:      make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.run(flushInterval)
+	return s
+}
+
+func (s *WebhookSink) Emit(ctx context.Context, event Event) error {
+	s.mu.Lock()
+	s.pending = append(s.pending, event)
+	full := len(s.pending) >= s.batchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flush <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Close flushes any pending events and stops the batching worker.
+func (s *WebhookSink) Close() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+func (s *WebhookSink) run(flushInterval time.Duration) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flushBatch()
+		case <-s.flush:
+			s.flushBatch()
+		case <-s.done:
+			s.flushBatch()
+			return
+		}
+	}
+}
+
+func (s *WebhookSink) flushBatch() {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	batch := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	if err := s.postWithRetry(batch); err != nil {
+		log.Printf("webhook sink: dropping batch of %d events: %v", len(batch), err)
+	}
+}
+
+func (s *WebhookSink) postWithRetry(batch []Event) error {
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("marshal batch: %w", err)
+	}
+
+	var lastErr error
+	backoff := 100 * time.Millisecond
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(data))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return fmt.Errorf("after %d attempts: %w", s.maxRetries+1, lastErr)
+}
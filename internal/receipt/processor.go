@@ -2,10 +2,10 @@ package receipt
 
 import (
 	"encoding/json"
+	"errors"
 	"math"
 	"net/http"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
@@ -13,38 +13,49 @@ import (
 	"github.com/gorilla/mux"
 )
 
-// Item represents a product in the receipt.
-type Item struct {
-	ShortDescription string `json:"shortDescription"`
-	Price            string `json:"price"`
-}
+// maxRequestBodyBytes caps how large a receipt payload the server will
+// read; receipts are small JSON documents and shouldn't be megabytes.
+const maxRequestBodyBytes = 1 << 20 // 1 MB
 
-// Receipt represents a full receipt structure.
-type Receipt struct {
-	ID           string `json:"id,omitempty"`
-	Retailer     string `json:"retailer"`
-	PurchaseDate string `json:"purchaseDate"`
-	PurchaseTime string `json:"purchaseTime"`
-	Items        []Item `json:"items"`
-	Total        string `json:"total"`
+// Handler serves the receipt HTTP endpoints against a Store.
+type Handler struct {
+	store  Store
+	rules  RuleSet
+	events *Publisher
 }
 
-// Store for holding receipts in memory (in-memory storage for simplicity).
-var receiptStore = make(map[string]Receipt)
+// NewHandler returns a Handler backed by store, scoring receipts with
+// rules and reporting activity to events.
+func NewHandler(store Store, rules RuleSet, events *Publisher) *Handler {
+	return &Handler{store: store, rules: rules, events: events}
+}
 
 // ProcessReceipt handles the submission of receipts and returns a unique ID.
-func ProcessReceipt(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) ProcessReceipt(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+
 	var receipt Receipt
 	if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeDecodeError(w, err)
 		return
 	}
 
 	// Generate a unique ID for the receipt
 	receipt.ID = uuid.New().String()
 
-	// Store the receipt in the in-memory store
-	receiptStore[receipt.ID] = receipt
+	if err := h.store.Save(receipt); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.events.Publish(Event{
+		Type:       EventReceiptProcessed,
+		Timestamp:  time.Now(),
+		ReceiptID:  receipt.ID,
+		Retailer:   receipt.Retailer,
+		ItemCount:  len(receipt.Items),
+		TotalCents: receipt.TotalCents(),
+	})
 
 	// Return the receipt ID as JSON
 	w.Header().Set("Content-Type", "application/json")
@@ -52,61 +63,114 @@ func ProcessReceipt(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetPoints calculates and returns the points awarded for a specific receipt.
-func GetPoints(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetPoints(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	// Retrieve the receipt from the store
-	receipt, exists := receiptStore[id]
+	receipt, exists, err := h.store.Get(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	if !exists {
 		http.Error(w, "Receipt not found", http.StatusNotFound)
 		return
 	}
 
-	// Calculate the points
-	points := calculatePoints(receipt)
+	if receipt.Points() == nil {
+		points := calculatePoints(receipt, h.rules)
+		receipt.SetPoints(points)
+		if err := h.store.Save(receipt); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.events.Publish(Event{
+			Type:       EventPointsCalculated,
+			Timestamp:  time.Now(),
+			ReceiptID:  receipt.ID,
+			Retailer:   receipt.Retailer,
+			ItemCount:  len(receipt.Items),
+			TotalCents: receipt.TotalCents(),
+			Points:     *receipt.Points(),
+		})
+	}
+
+	h.events.Publish(Event{
+		Type:       EventReceiptFetched,
+		Timestamp:  time.Now(),
+		ReceiptID:  receipt.ID,
+		Retailer:   receipt.Retailer,
+		ItemCount:  len(receipt.Items),
+		TotalCents: receipt.TotalCents(),
+		Points:     *receipt.Points(),
+	})
 
 	// Return the points as JSON
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]int64{"points": points})
+	json.NewEncoder(w).Encode(map[string]int64{"points": *receipt.Points()})
+}
+
+// Rules returns the active scoring RuleSet so operators can introspect it.
+func (h *Handler) Rules(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.rules)
+}
+
+// writeDecodeError responds 400 with a structured {"error","field"} body. A
+// *ValidationError carries the offending field name; any other decode
+// failure (malformed JSON, wrong types) is reported against the body as a
+// whole.
+func writeDecodeError(w http.ResponseWriter, err error) {
+	field := "body"
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		field = verr.Field
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": err.Error(),
+		"field": field,
+	})
 }
 
 // calculatePoints calculates the points based on various rules.
-func calculatePoints(receipt Receipt) int64 {
+func calculatePoints(receipt Receipt, rules RuleSet) int64 {
 	points := int64(0)
 
-	// Rule 1: Points for retailer name length (alphanumeric characters only)
-	points += int64(len(getAlphanumericString(receipt.Retailer)))
+	// Rule 1: points for retailer name length (alphanumeric characters only)
+	points += int64(len(getAlphanumericString(receipt.Retailer))) * rules.RetailerAlphanumericPoints
 
-	// Rule 2: 50 points if total is a round dollar amount with no cents
-	if isRoundDollar(receipt.Total) {
-		points += 50
+	// Rule 2: bonus if total is a round dollar amount with no cents
+	if receipt.TotalCents()%100 == 0 {
+		points += rules.RoundDollarBonus
 	}
 
-	// Rule 3: 25 points if total is a multiple of 0.25
-	if isMultipleOfQuarter(receipt.Total) {
-		points += 25
+	// Rule 3: bonus if total is a multiple of 0.25
+	if receipt.TotalCents()%25 == 0 {
+		points += rules.QuarterMultipleBonus
 	}
 
-	// Rule 4: Points for number of items (5 points for every 2 items)
-	points += int64((len(receipt.Items) / 2) * 5)
+	// Rule 4: bonus for every pair of items
+	points += int64(len(receipt.Items)/2) * rules.ItemsPairBonus
 
-	// Rule 5: Points based on item description length (multiple of 3)
+	// Rule 5: bonus based on item description length being a qualifying multiple
 	for _, item := range receipt.Items {
-		if len(strings.TrimSpace(item.ShortDescription))%3 == 0 {
-			price, _ := parsePrice(item.Price)
-			points += int64(math.Ceil(price * 0.2)) // Rounded up price * 0.2
+		if rules.DescriptionMultiple > 0 && int64(len(strings.TrimSpace(item.ShortDescription)))%rules.DescriptionMultiple == 0 {
+			points += int64(math.Ceil(float64(item.PriceCents()) / 100 * rules.DescriptionPriceMultiplier))
 		}
 	}
 
-	// Rule 6: Points if purchase date day is odd
-	if isOddDay(receipt.PurchaseDate) {
-		points += 6
+	// Rule 6: bonus if purchase date day is odd
+	if receipt.PurchaseAt().Day()%2 != 0 {
+		points += rules.OddDayBonus
 	}
 
-	// Rule 7: Points if purchase time is between 2:00pm and 4:00pm
-	if isBetween2And4PM(receipt.PurchaseTime) {
-		points += 10
+	// Rule 7: bonus if purchase time falls within the configured window
+	if hour := int64(receipt.PurchaseAt().Hour()); hour >= rules.TimeWindowStart && hour < rules.TimeWindowEnd {
+		points += rules.TimeWindowBonus
 	}
 
 	return points
@@ -117,42 +181,3 @@ func getAlphanumericString(s string) string {
 	re := regexp.MustCompile("[^a-zA-Z0-9]")
 	return re.ReplaceAllString(s, "")
 }
-
-// isRoundDollar checks if the total is a round dollar amount (e.g., "10.00")
-func isRoundDollar(total string) bool {
-	// Check if total is a round dollar amount (e.g., "10.00")
-	return strings.HasSuffix(total, ".00")
-}
-
-// isMultipleOfQuarter checks if the total is a multiple of 0.25.
-func isMultipleOfQuarter(total string) bool {
-	// Convert the string total to float and check if divisible by 0.25
-	price, err := parsePrice(total)
-	if err != nil {
-		return false
-	}
-	return math.Mod(price, 0.25) == 0
-}
-
-// parsePrice parses a price string into a float64 value.
-func parsePrice(price string) (float64, error) {
-	return strconv.ParseFloat(price, 64)
-}
-
-// isOddDay checks if the day of the purchase date is odd.
-func isOddDay(date string) bool {
-	parsedDate, err := time.Parse("2006-01-02", date)
-	if err != nil {
-		return false
-	}
-	return parsedDate.Day()%2 != 0
-}
-
-// isBetween2And4PM checks if the purchase time is between 2:00 PM and 4:00 PM.
-func isBetween2And4PM(timeStr string) bool {
-	parsedTime, err := time.Parse("15:04", timeStr)
-	if err != nil {
-		return false
-	}
-	return parsedTime.Hour() >= 14 && parsedTime.Hour() < 16
-}
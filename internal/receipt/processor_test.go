@@ -0,0 +1,58 @@
+package receipt
+
+import "testing"
+
+func TestCalculatePoints(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want int64
+	}{
+		{
+			name: "target receipt",
+			json: `{
+				"retailer": "Target",
+				"purchaseDate": "2022-01-01",
+				"purchaseTime": "13:01",
+				"items": [
+					{"shortDescription": "Mountain Dew 12PK", "price": "6.49"},
+					{"shortDescription": "Emils Cheese Pizza", "price": "12.25"},
+					{"shortDescription": "Knorr Creamy Chicken", "price": "1.26"},
+					{"shortDescription": "Doritos Nacho Cheese", "price": "3.35"},
+					{"shortDescription": "   Klarbrunn 12-PK 12 FL OZ  ", "price": "12.00"}
+				],
+				"total": "35.35"
+			}`,
+			want: 28,
+		},
+		{
+			name: "corner market receipt",
+			json: `{
+				"retailer": "M&M Corner Market",
+				"purchaseDate": "2022-03-20",
+				"purchaseTime": "14:33",
+				"items": [
+					{"shortDescription": "Gatorade", "price": "2.25"},
+					{"shortDescription": "Gatorade", "price": "2.25"},
+					{"shortDescription": "Gatorade", "price": "2.25"},
+					{"shortDescription": "Gatorade", "price": "2.25"}
+				],
+				"total": "9.00"
+			}`,
+			want: 109,
+		},
+	}
+
+	rules := DefaultRuleSet()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Receipt
+			if err := r.UnmarshalJSON([]byte(tt.json)); err != nil {
+				t.Fatalf("UnmarshalJSON: %v", err)
+			}
+			if got := calculatePoints(r, rules); got != tt.want {
+				t.Errorf("calculatePoints() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
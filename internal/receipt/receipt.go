@@ -0,0 +1,170 @@
+package receipt
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Wire-format constraints from the public API spec.
+var (
+	retailerPattern    = regexp.MustCompile(`^[\w\s\-&]+$`)
+	amountPattern      = regexp.MustCompile(`^\d+\.\d{2}$`)
+	descriptionPattern = regexp.MustCompile(`^[\w\s\-]+$`)
+)
+
+// ValidationError reports a single invalid field on an inbound receipt. It
+// is returned from Receipt/Item UnmarshalJSON so callers can surface a
+// structured 400 instead of a raw decode error.
+type ValidationError struct {
+	Field string
+	Msg   string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Msg
+}
+
+func fieldError(field, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Field: field, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Item represents a product in the receipt.
+type Item struct {
+	ShortDescription string `json:"shortDescription"`
+	Price            string `json:"price"`
+
+	// priceCents is Price parsed into integer cents, populated by UnmarshalJSON.
+	priceCents int64
+}
+
+// PriceCents returns the item's price in integer cents.
+func (i Item) PriceCents() int64 {
+	return i.priceCents
+}
+
+// UnmarshalJSON validates an Item against the public API spec and parses
+// Price into priceCents, while preserving the original wire format in the
+// exported fields.
+func (i *Item) UnmarshalJSON(data []byte) error {
+	type alias Item
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(a.ShortDescription) == "" {
+		return fieldError("shortDescription", "shortDescription must not be empty")
+	}
+	if !descriptionPattern.MatchString(a.ShortDescription) {
+		return fieldError("shortDescription", "shortDescription %q does not match %s", a.ShortDescription, descriptionPattern)
+	}
+	if !amountPattern.MatchString(a.Price) {
+		return fieldError("price", "price %q does not match %s", a.Price, amountPattern)
+	}
+	cents, err := parseCents(a.Price)
+	if err != nil {
+		return fieldError("price", "price %q is not a valid amount: %v", a.Price, err)
+	}
+
+	*i = Item(a)
+	i.priceCents = cents
+	return nil
+}
+
+// Receipt represents a full receipt structure.
+type Receipt struct {
+	ID           string `json:"id,omitempty"`
+	Retailer     string `json:"retailer"`
+	PurchaseDate string `json:"purchaseDate"`
+	PurchaseTime string `json:"purchaseTime"`
+	Items        []Item `json:"items"`
+	Total        string `json:"total"`
+
+	// purchaseAt and totalCents are PurchaseDate/PurchaseTime and Total
+	// parsed into typed values, populated by UnmarshalJSON.
+	purchaseAt time.Time
+	totalCents int64
+
+	// points caches the result of calculatePoints so repeated GetPoints
+	// calls for the same receipt don't recompute it. It is a
+	// server-computed value, never accepted from the wire, so it is
+	// unexported; Store implementations that need to persist it do so
+	// out of band (see FileStore).
+	points *int64
+}
+
+// PurchaseAt returns the receipt's purchase date and time.
+func (r Receipt) PurchaseAt() time.Time {
+	return r.purchaseAt
+}
+
+// TotalCents returns the receipt's total in integer cents.
+func (r Receipt) TotalCents() int64 {
+	return r.totalCents
+}
+
+// Points returns the cached points total, or nil if it hasn't been
+// computed yet.
+func (r Receipt) Points() *int64 {
+	return r.points
+}
+
+// SetPoints caches points as the receipt's computed points total.
+func (r *Receipt) SetPoints(points int64) {
+	r.points = &points
+}
+
+// UnmarshalJSON validates a Receipt against the public API spec and parses
+// PurchaseDate/PurchaseTime/Total into typed values, while preserving the
+// original wire format in the exported fields. Note that points is
+// unexported, so `type alias Receipt` has no JSON tag for it and a client
+// can never set it by including "points" in the request body.
+func (r *Receipt) UnmarshalJSON(data []byte) error {
+	type alias Receipt
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+
+	if !retailerPattern.MatchString(a.Retailer) {
+		return fieldError("retailer", "retailer %q does not match %s", a.Retailer, retailerPattern)
+	}
+	if len(a.Items) == 0 {
+		return fieldError("items", "items must not be empty")
+	}
+	if !amountPattern.MatchString(a.Total) {
+		return fieldError("total", "total %q does not match %s", a.Total, amountPattern)
+	}
+	totalCents, err := parseCents(a.Total)
+	if err != nil {
+		return fieldError("total", "total %q is not a valid amount: %v", a.Total, err)
+	}
+	purchaseAt, err := time.Parse("2006-01-02 15:04", a.PurchaseDate+" "+a.PurchaseTime)
+	if err != nil {
+		return fieldError("purchaseDate", "purchaseDate %q / purchaseTime %q is not valid: %v", a.PurchaseDate, a.PurchaseTime, err)
+	}
+
+	*r = Receipt(a)
+	r.totalCents = totalCents
+	r.purchaseAt = purchaseAt
+	return nil
+}
+
+// parseCents parses an amount string matching amountPattern (e.g. "12.34")
+// into integer cents (e.g. 1234).
+func parseCents(amount string) (int64, error) {
+	parts := strings.SplitN(amount, ".", 2)
+	whole, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	cents, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return whole*100 + cents, nil
+}
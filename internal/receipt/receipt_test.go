@@ -0,0 +1,114 @@
+package receipt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestItemUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+		field   string
+	}{
+		{name: "valid", json: `{"shortDescription":"Mountain Dew 12PK","price":"6.49"}`},
+		{name: "empty description", json: `{"shortDescription":"","price":"6.49"}`, wantErr: true, field: "shortDescription"},
+		{name: "whitespace description", json: `{"shortDescription":"   ","price":"6.49"}`, wantErr: true, field: "shortDescription"},
+		{name: "description with bad characters", json: `{"shortDescription":"Dew!","price":"6.49"}`, wantErr: true, field: "shortDescription"},
+		{name: "malformed price", json: `{"shortDescription":"Dew","price":"6.4"}`, wantErr: true, field: "price"},
+		{name: "non-numeric price", json: `{"shortDescription":"Dew","price":"a.bc"}`, wantErr: true, field: "price"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var item Item
+			err := item.UnmarshalJSON([]byte(tt.json))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("UnmarshalJSON(%q) = nil, want error", tt.json)
+				}
+				var verr *ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("UnmarshalJSON(%q) error = %v, want *ValidationError", tt.json, err)
+				}
+				if verr.Field != tt.field {
+					t.Errorf("UnmarshalJSON(%q) field = %q, want %q", tt.json, verr.Field, tt.field)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalJSON(%q) = %v, want nil", tt.json, err)
+			}
+			if item.PriceCents() != 649 {
+				t.Errorf("PriceCents() = %d, want 649", item.PriceCents())
+			}
+		})
+	}
+}
+
+func TestReceiptUnmarshalJSON(t *testing.T) {
+	const validItem = `{"shortDescription":"Mountain Dew 12PK","price":"6.49"}`
+
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+		field   string
+	}{
+		{
+			name: "valid",
+			json: `{"retailer":"Target","purchaseDate":"2022-01-01","purchaseTime":"13:01","items":[` + validItem + `],"total":"6.49"}`,
+		},
+		{
+			name:    "bad retailer",
+			json:    `{"retailer":"Target!","purchaseDate":"2022-01-01","purchaseTime":"13:01","items":[` + validItem + `],"total":"6.49"}`,
+			wantErr: true,
+			field:   "retailer",
+		},
+		{
+			name:    "empty items",
+			json:    `{"retailer":"Target","purchaseDate":"2022-01-01","purchaseTime":"13:01","items":[],"total":"6.49"}`,
+			wantErr: true,
+			field:   "items",
+		},
+		{
+			name:    "malformed total",
+			json:    `{"retailer":"Target","purchaseDate":"2022-01-01","purchaseTime":"13:01","items":[` + validItem + `],"total":"6.4"}`,
+			wantErr: true,
+			field:   "total",
+		},
+		{
+			name:    "bad purchase date",
+			json:    `{"retailer":"Target","purchaseDate":"2022-13-01","purchaseTime":"13:01","items":[` + validItem + `],"total":"6.49"}`,
+			wantErr: true,
+			field:   "purchaseDate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Receipt
+			err := r.UnmarshalJSON([]byte(tt.json))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("UnmarshalJSON(%q) = nil, want error", tt.json)
+				}
+				var verr *ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("UnmarshalJSON(%q) error = %v, want *ValidationError", tt.json, err)
+				}
+				if verr.Field != tt.field {
+					t.Errorf("UnmarshalJSON(%q) field = %q, want %q", tt.json, verr.Field, tt.field)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalJSON(%q) = %v, want nil", tt.json, err)
+			}
+			if r.TotalCents() != 649 {
+				t.Errorf("TotalCents() = %d, want 649", r.TotalCents())
+			}
+		})
+	}
+}
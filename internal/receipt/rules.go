@@ -0,0 +1,62 @@
+package receipt
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// RuleSet holds the tunable point-award values used by calculatePoints.
+// It is loaded from a TOML config file so operators can tune scoring
+// without recompiling.
+type RuleSet struct {
+	// RetailerAlphanumericPoints is awarded per alphanumeric character in
+	// the retailer name.
+	RetailerAlphanumericPoints int64 `toml:"retailer_alphanumeric_points" json:"retailerAlphanumericPoints"`
+	// RoundDollarBonus is awarded when the total has no cents.
+	RoundDollarBonus int64 `toml:"round_dollar_bonus" json:"roundDollarBonus"`
+	// QuarterMultipleBonus is awarded when the total is a multiple of $0.25.
+	QuarterMultipleBonus int64 `toml:"quarter_multiple_bonus" json:"quarterMultipleBonus"`
+	// ItemsPairBonus is awarded for every two items on the receipt.
+	ItemsPairBonus int64 `toml:"items_pair_bonus" json:"itemsPairBonus"`
+	// DescriptionMultiple is the length multiple that qualifies a trimmed
+	// item description for the description-price bonus.
+	DescriptionMultiple int64 `toml:"description_multiple" json:"descriptionMultiple"`
+	// DescriptionPriceMultiplier scales a qualifying item's price (in
+	// dollars) into bonus points, rounded up.
+	DescriptionPriceMultiplier float64 `toml:"description_price_multiplier" json:"descriptionPriceMultiplier"`
+	// OddDayBonus is awarded when the purchase date's day is odd.
+	OddDayBonus int64 `toml:"odd_day_bonus" json:"oddDayBonus"`
+	// TimeWindowStart and TimeWindowEnd are the hours (24h, start
+	// inclusive, end exclusive) during which TimeWindowBonus applies.
+	TimeWindowStart int64 `toml:"time_window_start" json:"timeWindowStart"`
+	TimeWindowEnd   int64 `toml:"time_window_end" json:"timeWindowEnd"`
+	TimeWindowBonus int64 `toml:"time_window_bonus" json:"timeWindowBonus"`
+}
+
+// DefaultRuleSet returns the RuleSet matching the service's original,
+// hard-coded scoring behavior.
+func DefaultRuleSet() RuleSet {
+	return RuleSet{
+		RetailerAlphanumericPoints: 1,
+		RoundDollarBonus:           50,
+		QuarterMultipleBonus:       25,
+		ItemsPairBonus:             5,
+		DescriptionMultiple:        3,
+		DescriptionPriceMultiplier: 0.2,
+		OddDayBonus:                6,
+		TimeWindowStart:            14,
+		TimeWindowEnd:              16,
+		TimeWindowBonus:            10,
+	}
+}
+
+// LoadRuleSet reads a RuleSet from a TOML config file at path, starting
+// from DefaultRuleSet so an omitted field keeps its default value.
+func LoadRuleSet(path string) (RuleSet, error) {
+	rules := DefaultRuleSet()
+	if _, err := toml.DecodeFile(path, &rules); err != nil {
+		return RuleSet{}, fmt.Errorf("load rules config %s: %w", path, err)
+	}
+	return rules, nil
+}
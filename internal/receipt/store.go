@@ -0,0 +1,176 @@
+package receipt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// Store persists receipts and their computed points across requests.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Save creates or updates the receipt identified by receipt.ID.
+	Save(r Receipt) error
+	// Get returns the receipt for id, or ok=false if it doesn't exist.
+	Get(id string) (r Receipt, ok bool, err error)
+	// List returns all stored receipts in no particular order.
+	List() ([]Receipt, error)
+}
+
+// MemoryStore is an in-memory Store. It does not survive process restarts.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	receipts map[string]Receipt
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{receipts: make(map[string]Receipt)}
+}
+
+func (s *MemoryStore) Save(r Receipt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.receipts[r.ID] = r
+	return nil
+}
+
+func (s *MemoryStore) Get(id string) (Receipt, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.receipts[id]
+	return r, ok, nil
+}
+
+func (s *MemoryStore) List() ([]Receipt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Receipt, 0, len(s.receipts))
+	for _, r := range s.receipts {
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// logEntry is the on-disk shape of one FileStore log line. Receipt's
+// points cache is unexported (it's server-computed, never accepted from
+// the wire), so it's round-tripped here alongside the receipt rather than
+// through Receipt's own JSON encoding.
+type logEntry struct {
+	Receipt Receipt `json:"receipt"`
+	Points  *int64  `json:"points,omitempty"`
+}
+
+// FileStore is a Store backed by a JSON-lines append-only log. Every Save
+// appends the receipt's current state as one JSON line; on startup the log
+// is replayed in order to rebuild an in-memory view, so later entries for
+// the same ID (e.g. a cached points update) override earlier ones.
+type FileStore struct {
+	mem  *MemoryStore
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileStore opens (creating if necessary) the log at path, replays it to
+// rebuild state, and returns a FileStore ready to accept writes.
+//
+// A process killed mid-write can leave a torn, unterminated line at the end
+// of the log. Since that's exactly the corruption a crash produces (and
+// crash recovery is the whole point of this store), replay tolerates a
+// malformed trailing line: it's logged and dropped, along with any partial
+// bytes after the last good line, rather than failing startup. A malformed
+// line anywhere else in the log is treated as real corruption and returned
+// as an error.
+func NewFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open receipt log: %w", err)
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("read receipt log: %w", err)
+	}
+
+	mem := NewMemoryStore()
+	var validEnd int64
+	for pos := 0; pos < len(data); {
+		lineEnd := len(data)
+		line := data[pos:]
+		if nl := bytes.IndexByte(line, '\n'); nl >= 0 {
+			line = line[:nl]
+			lineEnd = pos + nl + 1
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var entry logEntry
+			if err := json.Unmarshal(trimmed, &entry); err != nil {
+				if lineEnd < len(data) {
+					f.Close()
+					return nil, fmt.Errorf("replay receipt log: %w", err)
+				}
+				log.Printf("receipt log: dropping truncated trailing line (likely a crash mid-write): %v", err)
+				break
+			}
+			r := entry.Receipt
+			if entry.Points != nil {
+				r.SetPoints(*entry.Points)
+			}
+			mem.receipts[r.ID] = r
+		}
+
+		pos = lineEnd
+		validEnd = int64(lineEnd)
+	}
+
+	if validEnd < int64(len(data)) {
+		if err := f.Truncate(validEnd); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("truncate torn receipt log: %w", err)
+		}
+	}
+	if _, err := f.Seek(validEnd, os.SEEK_SET); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("seek receipt log: %w", err)
+	}
+
+	return &FileStore{mem: mem, file: f}, nil
+}
+
+func (s *FileStore) Save(r Receipt) error {
+	data, err := json.Marshal(logEntry{Receipt: r, Points: r.Points()})
+	if err != nil {
+		return fmt.Errorf("marshal receipt: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("append receipt log: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("sync receipt log: %w", err)
+	}
+
+	return s.mem.Save(r)
+}
+
+func (s *FileStore) Get(id string) (Receipt, bool, error) {
+	return s.mem.Get(id)
+}
+
+func (s *FileStore) List() ([]Receipt, error) {
+	return s.mem.List()
+}
+
+// Close releases the underlying log file handle.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,137 @@
+package receipt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func mustReceipt(t *testing.T, id, json string) Receipt {
+	t.Helper()
+	var r Receipt
+	if err := r.UnmarshalJSON([]byte(json)); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	r.ID = id
+	return r
+}
+
+func TestFileStoreReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "receipts.jsonl")
+
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+
+	r1 := mustReceipt(t, "r1", `{"retailer":"Target","purchaseDate":"2022-01-01","purchaseTime":"13:01","items":[{"shortDescription":"Dew","price":"6.49"}],"total":"6.49"}`)
+	r2 := mustReceipt(t, "r2", `{"retailer":"Walgreens","purchaseDate":"2022-01-02","purchaseTime":"08:00","items":[{"shortDescription":"Gum","price":"1.25"}],"total":"1.25"}`)
+	r2.SetPoints(42)
+
+	if err := s.Save(r1); err != nil {
+		t.Fatalf("Save(r1): %v", err)
+	}
+	if err := s.Save(r2); err != nil {
+		t.Fatalf("Save(r2): %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore (reopen): %v", err)
+	}
+	defer reopened.Close()
+
+	got, ok, err := reopened.Get("r1")
+	if err != nil || !ok {
+		t.Fatalf("Get(r1) = %v, %v, %v", got, ok, err)
+	}
+	if got.Retailer != "Target" {
+		t.Errorf("r1.Retailer = %q, want %q", got.Retailer, "Target")
+	}
+
+	got, ok, err = reopened.Get("r2")
+	if err != nil || !ok {
+		t.Fatalf("Get(r2) = %v, %v, %v", got, ok, err)
+	}
+	if got.Points() == nil || *got.Points() != 42 {
+		t.Errorf("r2.Points() = %v, want 42", got.Points())
+	}
+}
+
+func TestFileStoreReplayTolerateTornTrailingLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "receipts.jsonl")
+
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	r1 := mustReceipt(t, "r1", `{"retailer":"Target","purchaseDate":"2022-01-01","purchaseTime":"13:01","items":[{"shortDescription":"Dew","price":"6.49"}],"total":"6.49"}`)
+	if err := s.Save(r1); err != nil {
+		t.Fatalf("Save(r1): %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Simulate a process killed mid-Write: append a second, unterminated
+	// and truncated JSON line.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open for append: %v", err)
+	}
+	if _, err := f.WriteString(`{"receipt":{"id":"r2","retailer":"Walgr`); err != nil {
+		t.Fatalf("write torn line: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore should tolerate a torn trailing line, got error: %v", err)
+	}
+	defer reopened.Close()
+
+	if _, ok, _ := reopened.Get("r1"); !ok {
+		t.Error("r1 should have survived replay")
+	}
+	if _, ok, _ := reopened.Get("r2"); ok {
+		t.Error("r2 (torn entry) should not have been replayed")
+	}
+
+	if err := reopened.Save(mustReceipt(t, "r3", `{"retailer":"Costco","purchaseDate":"2022-01-03","purchaseTime":"09:00","items":[{"shortDescription":"Soda","price":"3.00"}],"total":"3.00"}`)); err != nil {
+		t.Fatalf("Save(r3) after reopen: %v", err)
+	}
+	if err := reopened.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The torn bytes should have been truncated away, so a third replay
+	// sees a clean log with both r1 and r3 and no lingering corruption.
+	final, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore (final replay): %v", err)
+	}
+	defer final.Close()
+	if _, ok, _ := final.Get("r1"); !ok {
+		t.Error("r1 should still be present after the torn line was truncated")
+	}
+	if _, ok, _ := final.Get("r3"); !ok {
+		t.Error("r3 should be present after the torn line was truncated")
+	}
+}
+
+func TestFileStoreReplayRejectsMidLogCorruption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "receipts.jsonl")
+
+	if err := os.WriteFile(path, []byte("not valid json\n{\"receipt\":{\"id\":\"r1\"}}\n"), 0o644); err != nil {
+		t.Fatalf("seed corrupt log: %v", err)
+	}
+
+	if _, err := NewFileStore(path); err == nil {
+		t.Fatal("NewFileStore should reject a malformed line that isn't the trailing line")
+	}
+}